@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecszap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingSyncer struct {
+	mu     sync.Mutex
+	writes [][]byte
+	syncs  int
+}
+
+func (c *countingSyncer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = append(c.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (c *countingSyncer) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncs++
+	return nil
+}
+
+func (c *countingSyncer) writeCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.writes)
+}
+
+func TestBufferedWriteSyncerOversizedWriteBypassesBuffer(t *testing.T) {
+	underlying := &countingSyncer{}
+	s := &BufferedWriteSyncer{WS: underlying, Size: 16, FlushInterval: time.Hour}
+	defer s.Stop()
+
+	if _, err := s.Write([]byte("short")); err != nil {
+		t.Fatalf("Write(short): %v", err)
+	}
+	if n := underlying.writeCount(); n != 0 {
+		t.Fatalf("expected short write to stay buffered, got %d underlying writes", n)
+	}
+
+	big := make([]byte, 64)
+	if _, err := s.Write(big); err != nil {
+		t.Fatalf("Write(big): %v", err)
+	}
+	// The oversized write must flush the already-buffered "short" data and
+	// then go straight to the underlying syncer, for two writes total.
+	if n := underlying.writeCount(); n != 2 {
+		t.Fatalf("expected 2 underlying writes after oversized write, got %d", n)
+	}
+}
+
+func TestBufferedWriteSyncerStopIsIdempotent(t *testing.T) {
+	underlying := &countingSyncer{}
+	s := &BufferedWriteSyncer{WS: underlying, Size: 1024, FlushInterval: time.Hour}
+
+	if _, err := s.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n := underlying.writeCount(); n != 0 {
+		t.Fatalf("expected write to stay buffered before Stop, got %d underlying writes", n)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+
+	if n := underlying.writeCount(); n != 1 {
+		t.Fatalf("expected buffered data to be flushed exactly once by Stop, got %d writes", n)
+	}
+}
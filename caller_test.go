@@ -0,0 +1,88 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecszap
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCallerEncoderUnmarshalText(t *testing.T) {
+	const full = "/home/build/example.com/foo/bar/baz.go"
+	caller := zapcore.NewEntryCaller(0, full, 42, true)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"full", "full", full},
+		{"package", "package", "bar/baz.go"},
+		{"module", "module=example.com/foo", "example.com/foo/bar/baz.go"},
+		{"depth 2", "depth=2", "bar/baz.go"},
+		{"depth 3", "depth=3", "foo/bar/baz.go"},
+		{"empty defaults to short", "", "bar/baz.go"},
+		{"unrecognized defaults to short", "bogus", "bar/baz.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e CallerEncoder
+			if err := e.UnmarshalText([]byte(tt.text)); err != nil {
+				t.Fatalf("UnmarshalText(%q): %v", tt.text, err)
+			}
+
+			cfg := ecsEncoderConfig
+			cfg.EncodeCaller = zapcore.CallerEncoder(e)
+			got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Caller: caller})
+
+			origin, ok := got["log.origin"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("log.origin missing or of unexpected type: %#v", got["log.origin"])
+			}
+			if origin["file.name"] != tt.want {
+				t.Errorf("file.name = %v, want %v", origin["file.name"], tt.want)
+			}
+		})
+	}
+}
+
+func TestCallerEncoderUnmarshalTextInvalidDepth(t *testing.T) {
+	tests := []string{"depth=-1", "depth=0", "depth=notanumber"}
+	for _, text := range tests {
+		var e CallerEncoder
+		if err := e.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q): expected error, got nil", text)
+		}
+	}
+}
+
+func TestNewCallerEncoder(t *testing.T) {
+	const full = "/home/build/example.com/foo/bar/baz.go"
+	caller := zapcore.NewEntryCaller(0, full, 42, true)
+
+	cfg := ecsEncoderConfig
+	cfg.EncodeCaller = zapcore.CallerEncoder(NewCallerEncoder(DepthCallerPathMode(1)))
+	got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Caller: caller})
+
+	origin := got["log.origin"].(map[string]interface{})
+	if want := "baz.go"; origin["file.name"] != want {
+		t.Errorf("file.name = %v, want %v", origin["file.name"], want)
+	}
+}
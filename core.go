@@ -0,0 +1,134 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecszap
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// EncoderConfig holds the configurable parts of the ECS compliant zapcore.Core
+// returned by NewCore. Apart from EncodeTime and CallerPathMode, which fall
+// back to RFC3339UTCTimeEncoder and ShortCallerPathMode when nil, NewCore uses
+// the fields of cfg as given, with no fallback for zero values: construct an
+// EncoderConfig via NewDefaultEncoderConfig to get ecszap's defaults, then
+// override individual fields. In particular, per the OmitKey convention, setting TimeKey,
+// CallerKey, StacktraceKey, FunctionKey or LevelKey to "" omits that field
+// from the encoded JSON rather than falling back to a default.
+type EncoderConfig struct {
+	EncodeDuration zapcore.DurationEncoder
+	EncodeName     zapcore.NameEncoder
+
+	// EncodeTime, if set, overrides the default RFC3339UTCTimeEncoder used to
+	// serialize the ECS `@timestamp` field. It can be populated declaratively
+	// from JSON/YAML configuration via the `time_encoder` tag, see TimeEncoder.
+	EncodeTime TimeEncoder `json:"time_encoder" yaml:"time_encoder"`
+
+	// TimeKey, CallerKey, StacktraceKey, FunctionKey and LevelKey name the
+	// corresponding ECS fields. Following zapcore's OmitKey convention, setting
+	// any of them to "" omits that field from the encoded JSON entirely.
+	// FunctionKey only switches the "function" subfield of log.origin on or
+	// off: for any non-empty value the subfield is still named "function".
+	// NewDefaultEncoderConfig populates these with ecszap's standard ECS keys.
+	TimeKey       string
+	CallerKey     string
+	StacktraceKey string
+	FunctionKey   string
+	LevelKey      string
+
+	// CallerPathMode, if set, overrides the default ShortCallerPathMode used to
+	// render the caller's file path, e.g. DepthCallerPathMode(2). FunctionKey
+	// is honored independently of this mode, so OmitKey suppression of the
+	// "function" subfield always applies.
+	CallerPathMode CallerPathMode
+
+	// BufferSize, if positive, opts into wrapping the WriteSyncer passed to
+	// NewCore in a BufferedWriteSyncer with this Size. BufferFlushInterval is
+	// passed through as its FlushInterval, defaulting to 30s if left zero.
+	BufferSize          int
+	BufferFlushInterval time.Duration
+}
+
+// NewDefaultEncoderConfig returns an EncoderConfig populated with ecszap's
+// default settings.
+func NewDefaultEncoderConfig() EncoderConfig {
+	return EncoderConfig{
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+		EncodeTime:     RFC3339UTCTimeEncoder,
+		TimeKey:        "@timestamp",
+		CallerKey:      "log.origin",
+		StacktraceKey:  "log.origin.stacktrace",
+		FunctionKey:    "function",
+		LevelKey:       "log.level",
+		CallerPathMode: ShortCallerPathMode(),
+	}
+}
+
+// ecsEncoderConfig is the zapcore.EncoderConfig used to produce ECS compliant
+// JSON log lines. Fields that are exposed for customization via EncoderConfig
+// are overwritten in NewCore before the encoder is built.
+var ecsEncoderConfig = zapcore.EncoderConfig{
+	TimeKey:        "@timestamp",
+	LevelKey:       "log.level",
+	NameKey:        "log.logger",
+	CallerKey:      "log.origin",
+	MessageKey:     "message",
+	StacktraceKey:  "log.origin.stacktrace",
+	LineEnding:     zapcore.DefaultLineEnding,
+	EncodeLevel:    zapcore.LowercaseLevelEncoder,
+	EncodeTime:     RFC3339UTCTimeEncoder,
+	EncodeCaller:   ShortCallerEncoder,
+}
+
+// NewCore creates a zapcore.Core that writes log entries as ECS compliant
+// JSON. The returned close function must be called on shutdown: when
+// cfg.BufferSize is set it stops the background BufferedWriteSyncer flush
+// goroutine and then flushes any buffered data; otherwise it is a no-op.
+func NewCore(cfg EncoderConfig, ws zapcore.WriteSyncer, enab zapcore.LevelEnabler) (zapcore.Core, func() error) {
+	encoderConfig := ecsEncoderConfig
+	encoderConfig.EncodeDuration = cfg.EncodeDuration
+	encoderConfig.EncodeName = cfg.EncodeName
+	if cfg.EncodeTime != nil {
+		encoderConfig.EncodeTime = zapcore.TimeEncoder(cfg.EncodeTime)
+	}
+	encoderConfig.TimeKey = cfg.TimeKey
+	encoderConfig.CallerKey = cfg.CallerKey
+	encoderConfig.StacktraceKey = cfg.StacktraceKey
+	encoderConfig.LevelKey = cfg.LevelKey
+	pathMode := cfg.CallerPathMode
+	if pathMode == nil {
+		pathMode = ShortCallerPathMode()
+	}
+	encoderConfig.EncodeCaller = zapcore.CallerEncoder(newCallerEncoder(pathMode, cfg.FunctionKey == ""))
+
+	closeFn := func() error { return nil }
+	if cfg.BufferSize > 0 {
+		bws := &BufferedWriteSyncer{
+			WS:            ws,
+			Size:          cfg.BufferSize,
+			FlushInterval: cfg.BufferFlushInterval,
+		}
+		ws = bws
+		closeFn = bws.Stop
+	}
+
+	enc := zapcore.NewJSONEncoder(encoderConfig)
+	return zapcore.NewCore(enc, ws, enab), closeFn
+}
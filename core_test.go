@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecszap
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type bufferSyncer struct {
+	buf bytes.Buffer
+}
+
+func (w *bufferSyncer) Write(p []byte) (int, error) { return w.buf.Write(p) }
+func (w *bufferSyncer) Sync() error                 { return nil }
+
+func TestNewCoreOmitKey(t *testing.T) {
+	cfg := NewDefaultEncoderConfig()
+	cfg.TimeKey = ""
+	cfg.LevelKey = ""
+	cfg.FunctionKey = ""
+
+	var ws bufferSyncer
+	core, closeFn := NewCore(cfg, &ws, zapcore.DebugLevel)
+	defer closeFn()
+
+	ent := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Message: "hi",
+		Caller:  zapcore.NewEntryCaller(0, "/home/build/example.com/foo/bar.go", 42, true),
+	}
+	if err := core.Write(ent, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(ws.buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", ws.buf.String(), err)
+	}
+
+	for _, key := range []string{"@timestamp", "log.level"} {
+		if v, ok := got[key]; ok {
+			t.Errorf("expected %q to be omitted, got %v", key, v)
+		}
+	}
+
+	origin, ok := got["log.origin"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("log.origin missing or of unexpected type: %#v", got["log.origin"])
+	}
+	if v, ok := origin["function"]; ok {
+		t.Errorf("expected function to be omitted, got %v", v)
+	}
+	if _, ok := origin["file.name"]; !ok {
+		t.Errorf("expected file.name to still be present")
+	}
+}
+
+func TestNewCoreOmitKeyWithCustomCallerPathMode(t *testing.T) {
+	cfg := NewDefaultEncoderConfig()
+	cfg.FunctionKey = ""
+	cfg.CallerPathMode = DepthCallerPathMode(2)
+
+	var ws bufferSyncer
+	core, closeFn := NewCore(cfg, &ws, zapcore.DebugLevel)
+	defer closeFn()
+
+	ent := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Message: "hi",
+		Caller:  zapcore.NewEntryCaller(0, "/home/build/example.com/foo/bar.go", 42, true),
+	}
+	if err := core.Write(ent, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(ws.buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", ws.buf.String(), err)
+	}
+
+	origin, ok := got["log.origin"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("log.origin missing or of unexpected type: %#v", got["log.origin"])
+	}
+	if v, ok := origin["function"]; ok {
+		t.Errorf("expected function to be omitted even with a custom CallerPathMode, got %v", v)
+	}
+	if want := "foo/bar.go"; origin["file.name"] != want {
+		t.Errorf("file.name = %v, want %v", origin["file.name"], want)
+	}
+}
@@ -18,6 +18,9 @@
 package ecszap
 
 import (
+	"fmt"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,14 +42,104 @@ type CallerEncoder func(zapcore.EntryCaller, zapcore.PrimitiveArrayEncoder)
 // in an ECS compliant way; serializing the full path of the file name
 // using the underlying zapcore.EntryCaller.
 func FullCallerEncoder(c zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-	encodeCaller(&caller{c, true}, enc)
+	encodeCaller(&caller{EntryCaller: c, pathMode: fullPathMode{}}, enc)
 }
 
 // ShortCallerEncoder serializes the file name, line and function from the caller
 // in an ECS compliant way; removing everything except the final directory from the
 // file name by calling the underlying zapcore.EntryCaller TrimmedPath().
 func ShortCallerEncoder(c zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-	encodeCaller(&caller{c, false}, enc)
+	encodeCaller(&caller{EntryCaller: c, pathMode: shortPathMode{}}, enc)
+}
+
+// CallerPathMode is a strategy for rendering the file path of a caller in the
+// ECS "log.origin" object. The modes below, constructed via ShortCallerPathMode,
+// FullCallerPathMode, PackageCallerPathMode, ModuleCallerPathMode and
+// DepthCallerPathMode, are the only valid implementations.
+type CallerPathMode interface {
+	path(c zapcore.EntryCaller) string
+}
+
+// NewCallerEncoder builds a CallerEncoder that renders the file path of a
+// caller according to the given CallerPathMode.
+func NewCallerEncoder(mode CallerPathMode) CallerEncoder {
+	return func(c zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		encodeCaller(&caller{EntryCaller: c, pathMode: mode}, enc)
+	}
+}
+
+type fullPathMode struct{}
+
+func (fullPathMode) path(c zapcore.EntryCaller) string {
+	return c.File
+}
+
+// ShortCallerPathMode trims everything except the final directory from the
+// file name, as used by ShortCallerEncoder.
+func ShortCallerPathMode() CallerPathMode { return shortPathMode{} }
+
+type shortPathMode struct{}
+
+func (shortPathMode) path(c zapcore.EntryCaller) string {
+	file := c.TrimmedPath()
+	return file[:strings.LastIndex(file, ":")]
+}
+
+// FullCallerPathMode renders the full, unmodified absolute file path, as used
+// by FullCallerEncoder.
+func FullCallerPathMode() CallerPathMode { return fullPathMode{} }
+
+// PackageCallerPathMode renders the file name prefixed with just its
+// containing directory, e.g. "pkg/file.go".
+func PackageCallerPathMode() CallerPathMode { return packagePathMode{} }
+
+type packagePathMode struct{}
+
+func (packagePathMode) path(c zapcore.EntryCaller) string {
+	dir, file := path.Split(c.File)
+	_, pkg := path.Split(strings.TrimSuffix(dir, "/"))
+	if pkg == "" {
+		return file
+	}
+	return pkg + "/" + file
+}
+
+// ModuleCallerPathMode renders the file path starting at importPath, with
+// everything before it stripped, e.g. ModuleCallerPathMode("example.com/foo")
+// turns "/home/build/example.com/foo/bar.go" into "example.com/foo/bar.go".
+// This keeps embedded source paths stable across build hosts that check out
+// the module at different filesystem locations. If importPath is not found in
+// the file path, the full path is used unchanged.
+func ModuleCallerPathMode(importPath string) CallerPathMode {
+	return modulePathMode{prefix: importPath}
+}
+
+type modulePathMode struct{ prefix string }
+
+func (m modulePathMode) path(c zapcore.EntryCaller) string {
+	if idx := strings.Index(c.File, m.prefix); idx >= 0 {
+		return c.File[idx:]
+	}
+	return c.File
+}
+
+// DepthCallerPathMode renders only the last depth path components of the file
+// path, e.g. DepthCallerPathMode(2) turns "/a/b/c/d.go" into "c/d.go".
+func DepthCallerPathMode(depth int) CallerPathMode {
+	return depthPathMode{depth: depth}
+}
+
+type depthPathMode struct{ depth int }
+
+func (m depthPathMode) path(c zapcore.EntryCaller) string {
+	if m.depth < 1 {
+		return c.File
+	}
+	parts := strings.Split(c.File, "/")
+	if len(parts) > m.depth {
+		parts = parts[len(parts)-m.depth:]
+	}
+	return strings.Join(parts, "/")
 }
 
 // RFC3339TimeEncoder serializes a time.Time to an RFC3339-formatted string
@@ -82,19 +175,91 @@ func RFC3339UTCTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	RFC3339TimeEncoder(t.In(time.UTC), enc)
 }
 
-// UnmarshalText creates a CallerEncoder function,
-// `full` is unmarshalled to FullCallerEncoder,
-// defaults to ShortCallerEncoder,
+// UnmarshalText creates a CallerEncoder function. The following forms are
+// recognized:
+//
+//   - "full": FullCallerEncoder
+//   - "package": NewCallerEncoder(PackageCallerPathMode())
+//   - "module=<import-path>": NewCallerEncoder(ModuleCallerPathMode(<import-path>))
+//   - "depth=<N>": NewCallerEncoder(DepthCallerPathMode(<N>))
+//
+// Anything else, including an empty string, defaults to ShortCallerEncoder.
 func (e *CallerEncoder) UnmarshalText(text []byte) error {
-	switch string(text) {
-	case "full":
+	s := string(text)
+	switch {
+	case s == "full":
 		*e = FullCallerEncoder
+	case s == "package":
+		*e = NewCallerEncoder(PackageCallerPathMode())
+	case strings.HasPrefix(s, "module="):
+		*e = NewCallerEncoder(ModuleCallerPathMode(strings.TrimPrefix(s, "module=")))
+	case strings.HasPrefix(s, "depth="):
+		depth, err := strconv.Atoi(strings.TrimPrefix(s, "depth="))
+		if err != nil {
+			return fmt.Errorf("invalid caller depth %q: %w", s, err)
+		}
+		if depth < 1 {
+			return fmt.Errorf("invalid caller depth %q: depth must be at least 1", s)
+		}
+		*e = NewCallerEncoder(DepthCallerPathMode(depth))
 	default:
 		*e = ShortCallerEncoder
 	}
 	return nil
 }
 
+// TimeEncoder is equivalent to zapcore.TimeEncoder, except that its UnmarshalText
+// method recognizes the ECS-flavoured forms below instead of zapcore's own set,
+// and defaults to RFC3339UTCTimeEncoder rather than zapcore's epoch-seconds default.
+type TimeEncoder func(time.Time, zapcore.PrimitiveArrayEncoder)
+
+// TimeEncoderOfLayout returns a TimeEncoder that formats times using the given
+// time.Time layout string, mirroring zapcore.TimeEncoderOfLayout.
+//
+// If enc supports AppendTimeLayout(t time.Time, layout string), it's used
+// instead of appending a pre-formatted string value.
+func TimeEncoderOfLayout(layout string) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		type appendTimeEncoder interface {
+			AppendTimeLayout(time.Time, string)
+		}
+		if enc, ok := enc.(appendTimeEncoder); ok {
+			enc.AppendTimeLayout(t, layout)
+			return
+		}
+		enc.AppendString(t.Format(layout))
+	}
+}
+
+// UnmarshalText creates a TimeEncoder function. The following forms are recognized:
+//
+//   - "rfc3339": RFC3339TimeEncoder
+//   - "rfc3339nano": RFC3339 with nanosecond precision, via TimeEncoderOfLayout(time.RFC3339Nano)
+//   - "iso8601": zapcore.ISO8601TimeEncoder
+//   - "epoch_micros": EpochMicrosTimeEncoder
+//   - "format=<layout>": TimeEncoderOfLayout(<layout>)
+//
+// Anything else, including an empty string, defaults to RFC3339UTCTimeEncoder.
+func (e *TimeEncoder) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "rfc3339":
+		*e = RFC3339TimeEncoder
+	case "rfc3339nano":
+		*e = TimeEncoder(TimeEncoderOfLayout(time.RFC3339Nano))
+	case "iso8601":
+		*e = TimeEncoder(zapcore.ISO8601TimeEncoder)
+	case "epoch_micros":
+		*e = EpochMicrosTimeEncoder
+	default:
+		if layout := strings.TrimPrefix(string(text), "format="); layout != string(text) {
+			*e = TimeEncoder(TimeEncoderOfLayout(layout))
+			return nil
+		}
+		*e = RFC3339UTCTimeEncoder
+	}
+	return nil
+}
+
 func encodeCaller(c *caller, enc zapcore.PrimitiveArrayEncoder) {
 	// this function can only be called internally so we have full control over it
 	// and can ensure that enc is always of type zapcore.ArrayEncoder
@@ -103,21 +268,26 @@ func encodeCaller(c *caller, enc zapcore.PrimitiveArrayEncoder) {
 	}
 }
 
+// newCallerEncoder builds a CallerEncoder honoring the given path mode and,
+// following the zapcore OmitKey convention, skipping the "function" subfield
+// entirely when omitFunction is set.
+func newCallerEncoder(mode CallerPathMode, omitFunction bool) CallerEncoder {
+	return func(c zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		encodeCaller(&caller{EntryCaller: c, pathMode: mode, omitFunction: omitFunction}, enc)
+	}
+}
+
 type caller struct {
 	zapcore.EntryCaller
-	fullPath bool
+	pathMode     CallerPathMode
+	omitFunction bool
 }
 
 func (c *caller) MarshalLogObject(enc zapcore.ObjectEncoder) error {
-	var file string
-	if c.fullPath {
-		file = c.File
-	} else {
-		file = c.TrimmedPath()
-		file = file[:strings.LastIndex(file, ":")]
+	if !c.omitFunction {
+		enc.AddString("function", c.Function)
 	}
-	enc.AddString("function", c.Function)
-	enc.AddString("file.name", file)
+	enc.AddString("file.name", c.pathMode.path(c.EntryCaller))
 	enc.AddInt("file.line", c.Line)
 	return nil
 }
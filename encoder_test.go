@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecszap
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeEntry encodes ent with cfg and decodes the resulting ECS JSON line
+// into a map, for asserting on individual fields.
+func encodeEntry(t *testing.T, cfg zapcore.EncoderConfig, ent zapcore.Entry) map[string]interface{} {
+	t.Helper()
+
+	enc := zapcore.NewJSONEncoder(cfg)
+	buf, err := enc.EncodeEntry(ent, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	defer buf.Free()
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", buf.String(), err)
+	}
+	return got
+}
+
+func TestTimeEncoderUnmarshalText(t *testing.T) {
+	when := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"rfc3339", "rfc3339", "2023-05-06T07:08:09.000Z"},
+		{"iso8601", "iso8601", "2023-05-06T07:08:09.000Z"},
+		{"custom layout", "format=2006-01-02", "2023-05-06"},
+		{"empty defaults to rfc3339 utc", "", "2023-05-06T07:08:09.000Z"},
+		{"unrecognized defaults to rfc3339 utc", "bogus", "2023-05-06T07:08:09.000Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e TimeEncoder
+			if err := e.UnmarshalText([]byte(tt.text)); err != nil {
+				t.Fatalf("UnmarshalText(%q): %v", tt.text, err)
+			}
+
+			cfg := ecsEncoderConfig
+			cfg.EncodeTime = zapcore.TimeEncoder(e)
+			got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Time: when})
+
+			if got["@timestamp"] != tt.want {
+				t.Errorf("@timestamp = %v, want %v", got["@timestamp"], tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeEncoderUnmarshalTextRFC3339Nano(t *testing.T) {
+	when := time.Date(2023, 5, 6, 7, 8, 9, 123000000, time.UTC)
+
+	var e TimeEncoder
+	if err := e.UnmarshalText([]byte("rfc3339nano")); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	cfg := ecsEncoderConfig
+	cfg.EncodeTime = zapcore.TimeEncoder(e)
+	got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Time: when})
+
+	want := when.Format(time.RFC3339Nano)
+	if got["@timestamp"] != want {
+		t.Errorf("@timestamp = %v, want %v", got["@timestamp"], want)
+	}
+}
+
+func TestTimeEncoderUnmarshalTextEpochMicros(t *testing.T) {
+	when := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	var e TimeEncoder
+	if err := e.UnmarshalText([]byte("epoch_micros")); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	cfg := ecsEncoderConfig
+	cfg.EncodeTime = zapcore.TimeEncoder(e)
+	got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Time: when})
+
+	want := float64(when.UnixNano()) / float64(time.Microsecond)
+	if got["@timestamp"] != want {
+		t.Errorf("@timestamp = %v, want %v", got["@timestamp"], want)
+	}
+}
+
+func TestTimeEncoderOfLayout(t *testing.T) {
+	when := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	cfg := ecsEncoderConfig
+	cfg.EncodeTime = TimeEncoderOfLayout("2006/01/02")
+	got := encodeEntry(t, cfg, zapcore.Entry{Level: zapcore.InfoLevel, Time: when})
+
+	if want := "2023/05/06"; got["@timestamp"] != want {
+		t.Errorf("@timestamp = %v, want %v", got["@timestamp"], want)
+	}
+}